@@ -8,6 +8,9 @@ import (
 
 	"github.com/Mm2PL/justgrep"
 	"github.com/jprochazk/go-twitch-irc/v4"
+
+	"github.com/jprochazk/tmi-rs/internal/corpus"
+	tmirs "github.com/jprochazk/tmi-rs/twitch"
 )
 
 func readInput() ([]string, error) {
@@ -69,4 +72,70 @@ func BenchmarkParse(b *testing.B) {
 			}
 		}
 	})
+
+	b.Run("tmi-rs", func(b *testing.B) {
+		var p tmirs.Parser
+		dst := tmirs.AcquireMessage()
+		defer tmirs.ReleaseMessage(dst)
+
+		for i := 0; i < b.N; i++ {
+			for _, line := range input {
+				if err := p.Parse(line, dst); err != nil {
+					fmt.Println(err)
+					b.FailNow()
+					return
+				}
+			}
+		}
+	})
+}
+
+// BenchmarkParseCorpus runs the same head-to-head as BenchmarkParse against
+// each of corpus's embedded, categorized corpora, so the comparison doesn't
+// depend on a local data.txt and covers message shapes data.txt's first
+// 1000 lines might not (e.g. USERNOTICE, adversarial input).
+func BenchmarkParseCorpus(b *testing.B) {
+	for _, name := range corpus.Names {
+		input := corpus.Load(name)
+
+		b.Run(name, func(b *testing.B) {
+			b.Run("justgrep", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					for _, line := range input {
+						message, err := justgrep.NewMessage(line)
+						if err != nil {
+							continue // adversarial corpus intentionally includes unparsable lines
+						}
+						_ = message
+					}
+				}
+			})
+
+			b.Run("go-twitch-irc", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					for _, line := range input {
+						message, err := twitch.ParseIRCMessage(line)
+						if err != nil {
+							continue // adversarial corpus intentionally includes unparsable lines
+						}
+						_ = message
+					}
+				}
+			})
+
+			b.Run("tmi-rs", func(b *testing.B) {
+				var p tmirs.Parser
+				dst := tmirs.AcquireMessage()
+				defer tmirs.ReleaseMessage(dst)
+
+				for i := 0; i < b.N; i++ {
+					for _, line := range input {
+						if err := p.Parse(line, dst); err != nil {
+							continue // adversarial corpus intentionally includes unparsable lines
+						}
+					}
+				}
+			})
+		})
+	}
 }
@@ -0,0 +1,238 @@
+// Command tmi-grep filters parsed Twitch IRC messages by structured
+// predicates (channel, command, tag, user, timestamp) instead of matching
+// raw lines with a regular expression.
+//
+// Usage:
+//
+//	tmi-grep [flags] [file]
+//
+// With no file argument, tmi-grep reads from stdin.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jprochazk/tmi-rs/twitch"
+	"github.com/jprochazk/tmi-rs/twitch/filter"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "tmi-grep:", err)
+		os.Exit(1)
+	}
+}
+
+type tagFlags map[string]string
+
+func (t tagFlags) String() string { return "" }
+
+func (t tagFlags) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("invalid --tag %q, want key=value", s)
+	}
+	t[key] = value
+	return nil
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("tmi-grep", flag.ContinueOnError)
+
+	channel := fs.String("channel", "", "only match messages targeting this channel")
+	command := fs.String("command", "", "only match messages with this IRC command")
+	userRegex := fs.String("user-regex", "", "only match messages whose sender nick matches this regex")
+	since := fs.String("since", "", "only match messages at or after this RFC3339 timestamp")
+	count := fs.Bool("c", false, "print only a count of matching messages")
+	lineNumber := fs.Bool("n", false, "prefix each match with its 1-based line number")
+	context := fs.Int("context", 0, "print N lines of raw context before and after each match")
+	jsonOutput := fs.Bool("json", false, "print matches as JSON objects, one per line")
+	tags := make(tagFlags)
+	fs.Var(tags, "tag", "only match messages with this tag set to this value (key=value, repeatable)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := filter.Options{
+		Channel: *channel,
+		Command: *command,
+		Tags:    tags,
+	}
+	if *userRegex != "" {
+		re, err := regexp.Compile(*userRegex)
+		if err != nil {
+			return fmt.Errorf("invalid --user-regex: %w", err)
+		}
+		opts.UserRegex = re
+	}
+	if *since != "" {
+		t, err := time.Parse(time.RFC3339, *since)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		opts.Since = t
+	}
+
+	in := stdin
+	if rest := fs.Args(); len(rest) > 0 {
+		f, err := os.Open(rest[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	return grep(in, stdout, opts, grepOptions{
+		count:      *count,
+		lineNumber: *lineNumber,
+		context:    *context,
+		json:       *jsonOutput,
+	})
+}
+
+type grepOptions struct {
+	count      bool
+	lineNumber bool
+	context    int
+	json       bool
+}
+
+type jsonMatch struct {
+	Line    int               `json:"line"`
+	Raw     string            `json:"raw"`
+	Command string            `json:"command"`
+	Channel string            `json:"channel,omitempty"`
+	Prefix  string            `json:"prefix,omitempty"`
+	Params  []string          `json:"params,omitempty"`
+	Tags    map[string]string `json:"tags,omitempty"`
+}
+
+// grep scans r line by line, printing (or counting) lines whose parsed
+// message satisfies opts. All lines are buffered so --context can print
+// lines surrounding a match; callers that only need -c can avoid this by
+// piping through a prior filter instead.
+func grep(r io.Reader, w io.Writer, opts filter.Options, g grepOptions) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	var matched int
+	printedUpTo := -1 // last line index (0-based) already printed as context or a match
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+
+		msg, err := twitch.ParseIRCMessage(line)
+		if err != nil {
+			continue
+		}
+		if !filter.Match(opts, msg) {
+			continue
+		}
+
+		matched++
+		if g.count {
+			continue
+		}
+
+		start := i - g.context
+		if start <= printedUpTo {
+			start = printedUpTo + 1
+		}
+		for j := start; j < i; j++ {
+			if j < 0 {
+				continue
+			}
+			printLine(w, lines[j], j, opts, g)
+		}
+
+		// The match line itself may already have been printed as trailing
+		// context of a previous, overlapping match; don't print it twice.
+		if i > printedUpTo {
+			if g.json {
+				printJSON(w, msg, i+1)
+			} else {
+				printMatch(w, line, i+1, g)
+			}
+			printedUpTo = i
+		}
+
+		end := i + g.context
+		if end >= len(lines) {
+			end = len(lines) - 1
+		}
+		for j := printedUpTo + 1; j <= end; j++ {
+			printLine(w, lines[j], j, opts, g)
+		}
+		if end > printedUpTo {
+			printedUpTo = end
+		}
+	}
+
+	if g.count {
+		fmt.Fprintln(w, matched)
+	}
+
+	return nil
+}
+
+// printLine prints the context line at lines[j]. If it's itself a match
+// (e.g. because it falls inside a neighboring match's context window too)
+// and JSON output is requested, it's printed as a JSON object like any
+// other match, rather than as a bare raw line.
+func printLine(w io.Writer, line string, j int, opts filter.Options, g grepOptions) {
+	if g.json {
+		if msg, err := twitch.ParseIRCMessage(line); err == nil && filter.Match(opts, msg) {
+			printJSON(w, msg, j+1)
+			return
+		}
+	}
+	printMatch(w, line, j+1, g)
+}
+
+func printMatch(w io.Writer, line string, lineNo int, g grepOptions) {
+	if g.lineNumber {
+		fmt.Fprintf(w, "%d:%s\n", lineNo, line)
+	} else {
+		fmt.Fprintln(w, line)
+	}
+}
+
+func printJSON(w io.Writer, msg *twitch.IRCMessage, lineNo int) {
+	m := jsonMatch{
+		Line:    lineNo,
+		Raw:     msg.Raw,
+		Command: msg.Command,
+		Prefix:  msg.Prefix,
+		Params:  msg.Params,
+	}
+	if len(msg.Params) > 0 {
+		m.Channel = strings.TrimPrefix(msg.Params[0], "#")
+	}
+	if len(msg.Tags) > 0 {
+		m.Tags = make(map[string]string, len(msg.Tags))
+		for _, t := range msg.Tags {
+			m.Tags[t.Key] = t.Value
+		}
+	}
+	enc := json.NewEncoder(w)
+	_ = enc.Encode(m)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jprochazk/tmi-rs/twitch/filter"
+)
+
+const sampleLog = `@mod=1;target-user-id=123 :foo!foo@foo.tmi.twitch.tv CLEARCHAT #forsen :baduser
+:foo!foo@foo.tmi.twitch.tv PRIVMSG #forsen :hello chat
+:foo!foo@foo.tmi.twitch.tv PRIVMSG #xqc :unrelated`
+
+func TestGrepFiltersByChannel(t *testing.T) {
+	var out bytes.Buffer
+	opts := filter.Options{Channel: "forsen"}
+
+	if err := grep(strings.NewReader(sampleLog), &out, opts, grepOptions{}); err != nil {
+		t.Fatalf("grep: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d matches, want 2: %q", len(lines), out.String())
+	}
+}
+
+func TestGrepCount(t *testing.T) {
+	var out bytes.Buffer
+	opts := filter.Options{Command: "PRIVMSG"}
+
+	if err := grep(strings.NewReader(sampleLog), &out, opts, grepOptions{count: true}); err != nil {
+		t.Fatalf("grep: %v", err)
+	}
+
+	if got := strings.TrimSpace(out.String()); got != "2" {
+		t.Fatalf("got count %q, want 2", got)
+	}
+}
+
+func TestGrepContext(t *testing.T) {
+	var out bytes.Buffer
+	opts := filter.Options{Command: "CLEARCHAT"}
+
+	if err := grep(strings.NewReader(sampleLog), &out, opts, grepOptions{context: 1}); err != nil {
+		t.Fatalf("grep: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines with --context 1, want 2: %q", len(lines), out.String())
+	}
+}
+
+// adjacentClearchatLog has two matches one line apart, so with --context 1
+// the second match also falls within the first match's trailing context.
+const adjacentClearchatLog = `:foo!foo@foo.tmi.twitch.tv PRIVMSG #forsen :hello chat
+@target-user-id=1 :foo!foo@foo.tmi.twitch.tv CLEARCHAT #forsen :baduser1
+@target-user-id=2 :foo!foo@foo.tmi.twitch.tv CLEARCHAT #forsen :baduser2
+:foo!foo@foo.tmi.twitch.tv PRIVMSG #forsen :bye chat`
+
+func TestGrepContextDoesNotDuplicateOverlappingLines(t *testing.T) {
+	var out bytes.Buffer
+	opts := filter.Options{Command: "CLEARCHAT"}
+
+	if err := grep(strings.NewReader(adjacentClearchatLog), &out, opts, grepOptions{context: 1}); err != nil {
+		t.Fatalf("grep: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	want := strings.Split(adjacentClearchatLog, "\n") // context 1 covers every line exactly once here
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d (no line should repeat): %q", len(lines), len(want), out.String())
+	}
+	for i, line := range lines {
+		if line != want[i] {
+			t.Fatalf("line %d: got %q, want %q", i, line, want[i])
+		}
+	}
+}
+
+func TestGrepJSONContextStillEmitsJSONForOverlappingMatch(t *testing.T) {
+	var out bytes.Buffer
+	opts := filter.Options{Command: "CLEARCHAT"}
+
+	if err := grep(strings.NewReader(adjacentClearchatLog), &out, opts, grepOptions{context: 1, json: true}); err != nil {
+		t.Fatalf("grep: %v", err)
+	}
+
+	var jsonLines int
+	sc := bufio.NewScanner(&out)
+	for sc.Scan() {
+		if strings.HasPrefix(sc.Text(), "{") {
+			jsonLines++
+		}
+	}
+
+	if jsonLines != 2 {
+		t.Fatalf("got %d JSON match objects, want 2 (one per CLEARCHAT, even though they share a context line): %q", jsonLines, out.String())
+	}
+}
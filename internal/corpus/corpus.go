@@ -0,0 +1,48 @@
+// Package corpus embeds the sample IRC line corpora under data/ so that
+// benchmarks and fuzz tests across the repo can share the same inputs
+// without depending on a working directory containing data.txt.
+//
+// It's named corpus, not testdata, despite living under internal/: the Go
+// toolchain ignores any directory literally named "testdata" when expanding
+// "./..." wildcards, which would silently drop this package (and its tests)
+// from `go build ./...`, `go vet ./...`, and `go test ./...`.
+package corpus
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+)
+
+//go:embed data/*.txt
+var files embed.FS
+
+// Names lists the corpora available via Load, in a fixed order suitable for
+// iterating in benchmarks.
+var Names = []string{
+	"privmsg",
+	"usernotice",
+	"clearchat_roomstate",
+	"adversarial",
+	"hugetag",
+}
+
+// Load returns the non-empty lines of the named corpus (one of Names). It
+// panics if name is not one of Names, since that's always a programming
+// error (a typo'd benchmark/test name), not a runtime condition to recover
+// from.
+func Load(name string) []string {
+	data, err := files.ReadFile("data/" + name + ".txt")
+	if err != nil {
+		panic(fmt.Sprintf("corpus: unknown corpus %q", name))
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	out := lines[:0]
+	for _, line := range lines {
+		if line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
@@ -0,0 +1,21 @@
+package corpus
+
+import "testing"
+
+func TestLoadAllCorpora(t *testing.T) {
+	for _, name := range Names {
+		lines := Load(name)
+		if len(lines) == 0 {
+			t.Errorf("corpus %q: got no lines", name)
+		}
+	}
+}
+
+func TestLoadUnknownCorpusPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected Load to panic for an unknown corpus")
+		}
+	}()
+	Load("does-not-exist")
+}
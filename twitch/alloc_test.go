@@ -0,0 +1,69 @@
+package twitch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jprochazk/tmi-rs/internal/corpus"
+)
+
+// TestParseAllocs fails if reusing a Parser and destination IRCMessage
+// across a whole corpus starts allocating more per message than the value
+// unescaping an escaped tag genuinely requires, once their Tags/Params
+// slices have grown to the corpus's high-water mark. A tag value containing
+// an IRCv3 escape sequence (e.g. "\s") can't be returned as a substring of
+// line, so unescapeTagValue allocates a new string for it on every call;
+// that's an expected, per-escaped-tag cost, not a slice-reuse regression.
+func TestParseAllocs(t *testing.T) {
+	for _, name := range corpus.Names {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			lines := corpus.Load(name)
+
+			var p Parser
+			dst := &IRCMessage{}
+
+			// Warm up dst's slice capacities on the real corpus before
+			// measuring, since the first parse of the largest message in it
+			// will always allocate to grow them.
+			for _, line := range lines {
+				_ = p.Parse(line, dst)
+			}
+
+			line := lines[len(lines)-1]
+			want := float64(escapedTagValueCount(line))
+
+			allocs := testing.AllocsPerRun(100, func() {
+				_ = p.Parse(line, dst)
+			})
+			if allocs > want {
+				t.Errorf("corpus %q: Parse allocated %.1f times per call after warm-up, want at most %.0f (one per escaped tag value)", name, allocs, want)
+			}
+		})
+	}
+}
+
+// escapedTagValueCount returns how many of line's tags have a raw value
+// containing an IRCv3 escape sequence, i.e. how many calls to
+// unescapeTagValue must allocate rather than return a substring of line.
+func escapedTagValueCount(line string) int {
+	if !strings.HasPrefix(line, "@") {
+		return 0
+	}
+	end := strings.IndexByte(line, ' ')
+	if end == -1 {
+		return 0
+	}
+
+	count := 0
+	for _, pair := range strings.Split(line[1:end], ";") {
+		if pair == "" {
+			continue
+		}
+		_, value, _ := strings.Cut(pair, "=")
+		if strings.ContainsRune(value, '\\') {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,79 @@
+package twitch
+
+import "strings"
+
+// Batch groups the messages referenced by an IRCv3 BATCH
+// (https://ircv3.net/specs/extensions/batch), in the order a BatchCollector
+// received them.
+type Batch struct {
+	// Ref is the batch reference tag shared by the BATCH start/end lines and
+	// every message tagged with `batch=<Ref>`.
+	Ref string
+	// Type is the batch type from the `BATCH +<ref> <type> ...` start line,
+	// e.g. "netsplit".
+	Type string
+	// Params holds any parameters after Type on the start line.
+	Params []string
+	// Messages holds every message seen with a matching `batch` tag while
+	// this batch was open.
+	Messages []*IRCMessage
+}
+
+// BatchCollector assembles IRCv3 BATCH start/end markers and the messages
+// tagged with a matching `batch` tag into complete Batch values. It has no
+// network or connection handling of its own; a client's read loop feeds it
+// every message in order and reacts when Feed reports a batch is done.
+type BatchCollector struct {
+	open map[string]*Batch
+}
+
+// NewBatchCollector returns a ready-to-use BatchCollector.
+func NewBatchCollector() *BatchCollector {
+	return &BatchCollector{open: make(map[string]*Batch)}
+}
+
+// Feed processes the next message in sequence. It returns the completed
+// Batch and true once its closing `BATCH -<ref>` line is seen; otherwise it
+// returns nil, false, having buffered msg into an open batch if msg's
+// `batch` tag names one.
+func (c *BatchCollector) Feed(msg *IRCMessage) (*Batch, bool) {
+	if msg.Command == "BATCH" && len(msg.Params) > 0 {
+		return c.feedBatchLine(msg)
+	}
+
+	if ref, ok := msg.Get("batch"); ok {
+		if b, ok := c.open[ref]; ok {
+			b.Messages = append(b.Messages, msg)
+		}
+	}
+
+	return nil, false
+}
+
+func (c *BatchCollector) feedBatchLine(msg *IRCMessage) (*Batch, bool) {
+	marker := msg.Params[0]
+	switch {
+	case strings.HasPrefix(marker, "+"):
+		ref := marker[1:]
+		b := &Batch{Ref: ref}
+		if len(msg.Params) > 1 {
+			b.Type = msg.Params[1]
+		}
+		if len(msg.Params) > 2 {
+			b.Params = append([]string(nil), msg.Params[2:]...)
+		}
+		c.open[ref] = b
+		return nil, false
+
+	case strings.HasPrefix(marker, "-"):
+		ref := marker[1:]
+		b, ok := c.open[ref]
+		if !ok {
+			return nil, false
+		}
+		delete(c.open, ref)
+		return b, true
+	}
+
+	return nil, false
+}
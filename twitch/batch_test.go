@@ -0,0 +1,35 @@
+package twitch
+
+import "testing"
+
+func TestBatchCollector(t *testing.T) {
+	c := NewBatchCollector()
+
+	lines := []string{
+		":irc.example.com BATCH +123 netsplit irc.example.com other.example.com",
+		"@batch=123 :nick!u@h PRIVMSG #chan :hello",
+		"@batch=123 :nick2!u@h PRIVMSG #chan :world",
+		":irc.example.com BATCH -123",
+	}
+
+	var done *Batch
+	for _, line := range lines {
+		msg, err := ParseIRCMessage(line)
+		if err != nil {
+			t.Fatalf("ParseIRCMessage(%q): %v", line, err)
+		}
+		if b, ok := c.Feed(msg); ok {
+			done = b
+		}
+	}
+
+	if done == nil {
+		t.Fatal("expected a completed batch")
+	}
+	if done.Ref != "123" || done.Type != "netsplit" {
+		t.Fatalf("got ref=%q type=%q", done.Ref, done.Type)
+	}
+	if len(done.Messages) != 2 {
+		t.Fatalf("got %d batched messages, want 2", len(done.Messages))
+	}
+}
@@ -0,0 +1,89 @@
+// Package filter implements predicates over parsed Twitch IRC messages, for
+// selecting messages by channel, command, tag, user, or timestamp instead of
+// matching raw lines with a regular expression.
+package filter
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jprochazk/tmi-rs/twitch"
+)
+
+// Options selects which messages match. A zero-valued field is not checked,
+// so the zero Options matches everything.
+type Options struct {
+	// Channel requires Params[0] (with or without a leading "#") to equal
+	// this channel name.
+	Channel string
+	// Command requires an exact, case-sensitive match on msg.Command.
+	Command string
+	// Tags requires each key to be present with exactly this value. Multiple
+	// entries are ANDed together.
+	Tags map[string]string
+	// UserRegex requires the message's sender nick (from its prefix) to
+	// match this pattern.
+	UserRegex *regexp.Regexp
+	// Since requires the message's tmi-sent-ts tag, if present, to be at or
+	// after this time. Messages without a tmi-sent-ts tag always pass this
+	// check.
+	Since time.Time
+}
+
+// Match reports whether msg satisfies every predicate set in o.
+func Match(o Options, msg *twitch.IRCMessage) bool {
+	if o.Command != "" && msg.Command != o.Command {
+		return false
+	}
+
+	if o.Channel != "" && channel(msg) != strings.TrimPrefix(o.Channel, "#") {
+		return false
+	}
+
+	for key, want := range o.Tags {
+		got, ok := msg.Get(key)
+		if !ok || got != want {
+			return false
+		}
+	}
+
+	if o.UserRegex != nil && !o.UserRegex.MatchString(user(msg)) {
+		return false
+	}
+
+	if !o.Since.IsZero() {
+		if ts, ok := msg.Get("tmi-sent-ts"); ok {
+			t, err := parseUnixMillis(ts)
+			if err == nil && t.Before(o.Since) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// channel returns msg's target channel, without the leading "#", or "" if
+// msg has no params.
+func channel(msg *twitch.IRCMessage) string {
+	if len(msg.Params) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(msg.Params[0], "#")
+}
+
+// user returns the nick portion of msg's prefix ("nick!user@host" -> "nick").
+func user(msg *twitch.IRCMessage) string {
+	nick, _, _ := strings.Cut(msg.Prefix, "!")
+	return nick
+}
+
+func parseUnixMillis(s string) (time.Time, error) {
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(ms), nil
+}
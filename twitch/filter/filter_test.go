@@ -0,0 +1,46 @@
+package filter
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/jprochazk/tmi-rs/twitch"
+)
+
+func parse(t *testing.T, line string) *twitch.IRCMessage {
+	t.Helper()
+	msg, err := twitch.ParseIRCMessage(line)
+	if err != nil {
+		t.Fatalf("ParseIRCMessage(%q): %v", line, err)
+	}
+	return msg
+}
+
+func TestMatch(t *testing.T) {
+	msg := parse(t, "@mod=1;target-user-id=123 :foo!foo@foo.tmi.twitch.tv CLEARCHAT #forsen :baduser")
+
+	cases := []struct {
+		name string
+		opts Options
+		want bool
+	}{
+		{"command match", Options{Command: "CLEARCHAT"}, true},
+		{"command mismatch", Options{Command: "PRIVMSG"}, false},
+		{"channel match with hash", Options{Channel: "#forsen"}, true},
+		{"channel match without hash", Options{Channel: "forsen"}, true},
+		{"channel mismatch", Options{Channel: "xqc"}, false},
+		{"tag match", Options{Tags: map[string]string{"target-user-id": "123"}}, true},
+		{"tag mismatch", Options{Tags: map[string]string{"target-user-id": "456"}}, false},
+		{"user regex match", Options{UserRegex: regexp.MustCompile("^foo$")}, true},
+		{"user regex mismatch", Options{UserRegex: regexp.MustCompile("^bar$")}, false},
+		{"combined", Options{Command: "CLEARCHAT", Channel: "forsen", Tags: map[string]string{"mod": "1"}}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := Match(c.opts, msg); got != c.want {
+				t.Errorf("Match() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+// Package twitch implements a parser for the IRC message format used by
+// Twitch chat (https://dev.twitch.tv/docs/irc/).
+package twitch
+
+import "strings"
+
+// IRCMessage is a single parsed IRC line, e.g.:
+//
+//	@badge-info=;badges=broadcaster/1;color=;display-name=foo :foo!foo@foo.tmi.twitch.tv PRIVMSG #foo :hello
+type IRCMessage struct {
+	// Raw is the original, unparsed line.
+	Raw string
+	// Tags holds the IRCv3 message tags (the `@key=value;...` prefix), if
+	// any, in the order they appeared on the wire. Client-only tags (keys
+	// prefixed with "+") are held separately in ClientTags, with the prefix
+	// stripped.
+	Tags []Tag
+	// ClientTags holds IRCv3 client-only tags (https://ircv3.net/specs/extensions/message-tags#client-only-tags),
+	// keyed without their leading "+".
+	ClientTags []Tag
+	// Prefix is the `:nick!user@host` portion, without the leading colon.
+	Prefix string
+	// Command is the IRC command or numeric reply, e.g. "PRIVMSG".
+	Command string
+	// Params holds the middle parameters followed by the trailing parameter,
+	// in order. The trailing parameter (after the first " :") is the final
+	// element, with its leading colon stripped.
+	Params []string
+}
+
+// ParseIRCMessage parses a single IRC line into a freshly allocated
+// IRCMessage. Callers that parse at a high rate should use Parser.Parse
+// with a reused destination (or AcquireMessage/ReleaseMessage) instead, to
+// avoid allocating a new tags slice, params slice, and IRCMessage per call.
+//
+// line must not contain the trailing "\r\n".
+func ParseIRCMessage(line string) (*IRCMessage, error) {
+	msg := &IRCMessage{}
+	var p Parser
+	if err := p.Parse(line, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// appendTags parses the `key=value;key=value` tag body of s, appending
+// server tags to dst and client-only tags (keys prefixed with "+", which is
+// stripped) to clientDst, and returns both extended slices. Passing slices
+// with spare capacity (e.g. from a reused IRCMessage) avoids allocating.
+//
+// Tag values are sliced directly from s when they contain no escape
+// sequences, so the returned Tags/ClientTags borrow s's backing array; once
+// s is no longer valid (e.g. after the line buffer it came from is reused),
+// those values must not be read. Values containing escapes are unescaped
+// into a freshly allocated string instead.
+// appendTags walks s by hand rather than calling strings.Split, which
+// always allocates its result slice; dst and clientDst's capacity is reused
+// instead.
+func appendTags(dst, clientDst []Tag, s string) (tags, clientTags []Tag) {
+	i, n := 0, len(s)
+	for i < n {
+		start := i
+		for i < n && s[i] != ';' {
+			i++
+		}
+		pair := s[start:i]
+		i++ // skip ';'
+
+		if pair == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, "=")
+		tag := Tag{Key: key, Value: unescapeTagValue(value)}
+		if strings.HasPrefix(key, "+") {
+			tag.Key = key[1:]
+			clientDst = append(clientDst, tag)
+			continue
+		}
+		dst = append(dst, tag)
+	}
+	return dst, clientDst
+}
+
+// EncodeTags renders tags back into the `key=value;key=value` wire format
+// (without a leading "@"), escaping values per the IRCv3 message-tags spec
+// so that appendTags round-trips the result.
+func EncodeTags(tags []Tag) string {
+	var b strings.Builder
+	for i, t := range tags {
+		if i > 0 {
+			b.WriteByte(';')
+		}
+		b.WriteString(t.Key)
+		if t.Value != "" {
+			b.WriteByte('=')
+			b.WriteString(escapeTagValue(t.Value))
+		}
+	}
+	return b.String()
+}
+
+// escapeTagValue applies the inverse of unescapeTagValue.
+func escapeTagValue(s string) string {
+	if !strings.ContainsAny(s, ";\\ \r\n") {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case ';':
+			b.WriteString(`\:`)
+		case ' ':
+			b.WriteString(`\s`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\n':
+			b.WriteString(`\n`)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// unescapeTagValue undoes the escaping described in the IRCv3 message-tags
+// spec: \: -> ;, \s -> space, \\ -> \, \r -> CR, \n -> LF. A trailing
+// backslash is passed through unchanged; for any other escape sequence the
+// backslash is dropped and the character after it is kept.
+func unescapeTagValue(s string) string {
+	if !strings.ContainsRune(s, '\\') {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i == len(s)-1 {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch s[i] {
+		case ':':
+			b.WriteByte(';')
+		case 's':
+			b.WriteByte(' ')
+		case '\\':
+			b.WriteByte('\\')
+		case 'r':
+			b.WriteByte('\r')
+		case 'n':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+type parseError string
+
+func (e parseError) Error() string { return "twitch: malformed IRC message: " + string(e) }
+
+func errMalformed(reason string) error { return parseError(reason) }
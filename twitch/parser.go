@@ -0,0 +1,147 @@
+package twitch
+
+import (
+	"strings"
+	"sync"
+)
+
+// Tag is a single IRCv3 message tag key/value pair.
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// Get returns the value of the first tag named key, if present.
+func (m *IRCMessage) Get(key string) (string, bool) {
+	return get(m.Tags, key)
+}
+
+// GetClient returns the value of the first client-only tag named key (the
+// name, without its "+" prefix), if present.
+func (m *IRCMessage) GetClient(key string) (string, bool) {
+	return get(m.ClientTags, key)
+}
+
+func get(tags []Tag, key string) (string, bool) {
+	for _, t := range tags {
+		if t.Key == key {
+			return t.Value, true
+		}
+	}
+	return "", false
+}
+
+// Parser parses IRC lines into caller-provided IRCMessage values, reusing
+// their Tags and Params slices across calls instead of allocating new ones
+// each time. The zero value is ready to use; a Parser holds no state of its
+// own and is safe for concurrent use as long as callers don't share a dst.
+type Parser struct{}
+
+// Parse parses line into dst, resetting and reusing dst's Tags and Params
+// slices (and their underlying arrays) rather than allocating new ones. On
+// error, dst may be left partially populated.
+//
+// Tag and Param values are sliced from line where possible; see appendTags
+// for the resulting borrow semantics.
+func (p *Parser) Parse(line string, dst *IRCMessage) error {
+	dst.Raw = line
+	dst.Prefix = ""
+	dst.Command = ""
+	dst.Tags = dst.Tags[:0]
+	dst.ClientTags = dst.ClientTags[:0]
+	dst.Params = dst.Params[:0]
+
+	rest := line
+
+	if strings.HasPrefix(rest, "@") {
+		end := strings.IndexByte(rest, ' ')
+		if end == -1 {
+			return errMalformed("missing content after tags")
+		}
+		dst.Tags, dst.ClientTags = appendTags(dst.Tags, dst.ClientTags, rest[1:end])
+		rest = rest[end+1:]
+		rest = strings.TrimPrefix(rest, " ")
+	}
+
+	if strings.HasPrefix(rest, ":") {
+		end := strings.IndexByte(rest, ' ')
+		if end == -1 {
+			return errMalformed("missing content after prefix")
+		}
+		dst.Prefix = rest[1:end]
+		rest = rest[end+1:]
+	}
+
+	if trailingStart := strings.Index(rest, " :"); trailingStart != -1 {
+		var command string
+		command, dst.Params = appendParams(dst.Params, rest[:trailingStart])
+		dst.Command = command
+		dst.Params = append(dst.Params, rest[trailingStart+2:])
+	} else if strings.HasPrefix(rest, ":") {
+		return errMalformed("missing command")
+	} else {
+		command, params := appendParams(dst.Params, rest)
+		dst.Command = command
+		dst.Params = params
+	}
+
+	if dst.Command == "" {
+		return errMalformed("missing command")
+	}
+
+	return nil
+}
+
+// appendParams splits s on spaces into a command and its params,
+// appending the params onto dst and returning the extended slice.
+//
+// This walks s by hand rather than calling strings.Fields, which always
+// allocates its result slice; dst's capacity is reused instead.
+func appendParams(dst []string, s string) (command string, params []string) {
+	i, n := 0, len(s)
+	for i < n {
+		for i < n && s[i] == ' ' {
+			i++
+		}
+		start := i
+		for i < n && s[i] != ' ' {
+			i++
+		}
+		if start == i {
+			break
+		}
+
+		field := s[start:i]
+		if command == "" {
+			command = field
+		} else {
+			dst = append(dst, field)
+		}
+	}
+	return command, dst
+}
+
+var messagePool = sync.Pool{
+	New: func() any { return new(IRCMessage) },
+}
+
+// AcquireMessage returns an IRCMessage from a shared pool, for use with
+// Parser.Parse. The returned message's Tags and Params slices are empty but
+// may retain capacity from a previous use. Callers must call ReleaseMessage
+// once they're done with it.
+func AcquireMessage() *IRCMessage {
+	return messagePool.Get().(*IRCMessage)
+}
+
+// ReleaseMessage returns m to the pool used by AcquireMessage, for reuse by
+// a future parse. m (and any strings borrowed from a line it was parsed
+// from, per appendTags) must not be used afterwards.
+func ReleaseMessage(m *IRCMessage) {
+	m.Raw = ""
+	m.Prefix = ""
+	m.Command = ""
+	m.Tags = m.Tags[:0]
+	m.ClientTags = m.ClientTags[:0]
+	m.Params = m.Params[:0]
+	messagePool.Put(m)
+}
@@ -0,0 +1,99 @@
+package twitch
+
+import "testing"
+
+func TestParserReusesSlices(t *testing.T) {
+	var p Parser
+	dst := &IRCMessage{}
+
+	if err := p.Parse("@mod=1;subscriber=0 :foo!foo@foo.tmi.twitch.tv PRIVMSG #foo :hello world", dst); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tagsPtr := &dst.Tags[:1][0]
+	tagsCap := cap(dst.Tags)
+
+	if err := p.Parse("PING :tmi.twitch.tv", dst); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dst.Tags) != 0 {
+		t.Fatalf("expected no tags, got %v", dst.Tags)
+	}
+	if cap(dst.Tags) != tagsCap {
+		t.Fatalf("expected Parse to keep reusing the same Tags capacity, got %d want %d", cap(dst.Tags), tagsCap)
+	}
+	dst.Tags = append(dst.Tags, Tag{})
+	if &dst.Tags[0] != tagsPtr {
+		t.Fatalf("expected Tags backing array to be reused across Parse calls")
+	}
+
+	if dst.Command != "PING" || len(dst.Params) != 1 || dst.Params[0] != "tmi.twitch.tv" {
+		t.Fatalf("unexpected parse result: %+v", dst)
+	}
+}
+
+func TestAcquireReleaseMessage(t *testing.T) {
+	var p Parser
+
+	m := AcquireMessage()
+	if err := p.Parse("PRIVMSG #foo :hi", m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Command != "PRIVMSG" {
+		t.Fatalf("got command %q", m.Command)
+	}
+	ReleaseMessage(m)
+
+	if len(m.Tags) != 0 || len(m.Params) != 0 || m.Command != "" {
+		t.Fatalf("expected ReleaseMessage to reset m, got %+v", m)
+	}
+}
+
+func TestClientTags(t *testing.T) {
+	msg, err := ParseIRCMessage("@mod=1;+example.com/foo=bar :foo!foo@foo.tmi.twitch.tv PRIVMSG #foo :hi")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := msg.Get("+example.com/foo"); ok {
+		t.Fatalf("client-only tag leaked into Tags")
+	}
+	if v, ok := msg.GetClient("example.com/foo"); !ok || v != "bar" {
+		t.Fatalf("got GetClient = %q, %v", v, ok)
+	}
+}
+
+func TestEncodeTagsRoundTrips(t *testing.T) {
+	const raw = `key1=value\:1;key2=;key3=va\slue;key4=with\\backslash`
+
+	msg, err := ParseIRCMessage("@" + raw + " PRIVMSG #foo :hi")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	encoded := EncodeTags(msg.Tags)
+	reparsed, err := ParseIRCMessage("@" + encoded + " PRIVMSG #foo :hi")
+	if err != nil {
+		t.Fatalf("unexpected error reparsing: %v", err)
+	}
+
+	if len(reparsed.Tags) != len(msg.Tags) {
+		t.Fatalf("got %d tags after round-trip, want %d", len(reparsed.Tags), len(msg.Tags))
+	}
+	for i, tag := range msg.Tags {
+		if reparsed.Tags[i] != tag {
+			t.Fatalf("tag %d: got %+v, want %+v", i, reparsed.Tags[i], tag)
+		}
+	}
+}
+
+func TestTagGet(t *testing.T) {
+	msg, err := ParseIRCMessage("@mod=1;subscriber=0 :foo!foo@foo.tmi.twitch.tv PRIVMSG #foo :hi")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, ok := msg.Get("mod"); !ok || v != "1" {
+		t.Fatalf("got Get(%q) = %q, %v", "mod", v, ok)
+	}
+	if _, ok := msg.Get("missing"); ok {
+		t.Fatalf("expected Get to report missing tag as absent")
+	}
+}
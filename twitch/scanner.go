@@ -0,0 +1,182 @@
+package twitch
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxLineLength is the MaxLineLength used by NewMessageScanner when
+// ScannerOptions.MaxLineLength is zero.
+const DefaultMaxLineLength = 16 * 1024
+
+// ErrLineTooLong is wrapped in the error returned by MessageScanner.Err
+// after Scan stops on a line that exceeds ScannerOptions.MaxLineLength while
+// ScannerOptions.TruncateLongLines is false, or on the Scan that follows a
+// truncated line whose end was not read.
+var ErrLineTooLong = errors.New("twitch: line exceeds MaxLineLength")
+
+// ScannerOptions configures a MessageScanner.
+type ScannerOptions struct {
+	// BufferSize is the initial size, in bytes, of the underlying
+	// bufio.Reader's buffer. Zero uses bufio's default.
+	BufferSize int
+	// MaxLineLength bounds how many bytes a single IRC line may occupy
+	// before TruncateLongLines or ErrLineTooLong applies. Zero uses
+	// DefaultMaxLineLength.
+	MaxLineLength int
+	// TruncateLongLines, if true, silently truncates lines longer than
+	// MaxLineLength to that length instead of failing the scan with
+	// ErrLineTooLong. The truncated line is still parsed as best-effort. If
+	// the rest of the line did not fit in the reader's buffer and was left
+	// unread, the following Scan stops with ErrLineTooLong.
+	TruncateLongLines bool
+}
+
+// MessageScanner reads newline-delimited IRC messages from an io.Reader and
+// parses each one into an *IRCMessage, one at a time. Unlike reading the
+// whole input up front, it never buffers more than MaxLineLength bytes (plus
+// one bufio.Reader buffer's worth) for a single line, which makes it safe to point at a live connection or an
+// untrusted/huge log file.
+//
+// A malicious or misbehaving server that sends a single line with no
+// terminator longer than MaxLineLength would otherwise force unbounded
+// buffering; MessageScanner rejects (or truncates) that line instead.
+type MessageScanner struct {
+	r       *bufio.Reader
+	opts    ScannerOptions
+	message *IRCMessage
+	err     error
+
+	// resyncFailed is set when TruncateLongLines emitted a truncated line
+	// whose real end we didn't read, leaving the reader mid-line; the next
+	// Scan reports that as an error rather than silently parsing garbage.
+	resyncFailed bool
+}
+
+// NewMessageScanner creates a MessageScanner that reads lines from r.
+func NewMessageScanner(r io.Reader, opts ScannerOptions) *MessageScanner {
+	if opts.MaxLineLength <= 0 {
+		opts.MaxLineLength = DefaultMaxLineLength
+	}
+
+	var br *bufio.Reader
+	if opts.BufferSize > 0 {
+		br = bufio.NewReaderSize(r, opts.BufferSize)
+	} else {
+		br = bufio.NewReader(r)
+	}
+
+	return &MessageScanner{r: br, opts: opts}
+}
+
+// Scan advances the scanner to the next message, parsing it and making it
+// available via Message. It returns false when there are no more messages,
+// either because the input is exhausted or because an error occurred; call
+// Err to distinguish the two.
+func (s *MessageScanner) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+
+	for {
+		line, err := s.readLine()
+		if err != nil {
+			if err != io.EOF {
+				s.err = err
+			}
+			return false
+		}
+		if line == "" {
+			// Blank lines (including the one trailing the final message, or
+			// an endless stream of keep-alive blanks) carry no content;
+			// skip them rather than failing the parse. Looping here rather
+			// than recursing keeps an arbitrarily long run of them from
+			// overflowing the stack.
+			continue
+		}
+
+		msg, err := ParseIRCMessage(line)
+		if err != nil {
+			s.err = err
+			return false
+		}
+
+		s.message = msg
+		return true
+	}
+}
+
+// readLine returns the next line with its terminator stripped, enforcing
+// MaxLineLength without ever buffering more than MaxLineLength bytes (plus
+// one bufio.Reader buffer's worth) for a single line.
+//
+// bufio.Reader.ReadString/ReadBytes grow an internal slice without bound
+// until they find the delimiter, so delegating to either of them would
+// still fully buffer an attacker's unterminated multi-megabyte line before
+// MaxLineLength is ever checked. Reading via ReadSlice instead lets us check
+// accumulated length after every buffer-sized fragment and bail as soon as
+// it's exceeded, instead of continuing to read in search of the newline.
+func (s *MessageScanner) readLine() (string, error) {
+	if s.resyncFailed {
+		s.resyncFailed = false
+		return "", fmt.Errorf("%w: reader position is unknown after a previous truncated line", ErrLineTooLong)
+	}
+
+	var line []byte
+
+	for {
+		// frag aliases the bufio.Reader's internal buffer, which is only
+		// valid until the next read call, so append (rather than retain)
+		// it into our own accumulator.
+		frag, err := s.r.ReadSlice('\n')
+		line = append(line, frag...)
+
+		if len(line) > s.opts.MaxLineLength {
+			if !s.opts.TruncateLongLines {
+				return "", fmt.Errorf("%w: at least %d bytes", ErrLineTooLong, len(line))
+			}
+			if err == bufio.ErrBufferFull {
+				// The real line continues past the truncation point. We
+				// don't keep reading to find where it ends - that would
+				// reintroduce the unbounded buffering MaxLineLength exists
+				// to prevent - so report desync on the next Scan instead of
+				// silently parsing whatever follows as if it were new.
+				s.resyncFailed = true
+			}
+			return string(trimLineEnding(line[:s.opts.MaxLineLength])), nil
+		}
+
+		switch err {
+		case bufio.ErrBufferFull:
+			continue // line continues past this fragment; keep accumulating
+		case io.EOF:
+			if len(line) == 0 {
+				return "", io.EOF
+			}
+			return string(trimLineEnding(line)), nil
+		case nil:
+			return string(trimLineEnding(line)), nil
+		default:
+			return "", err
+		}
+	}
+}
+
+func trimLineEnding(line []byte) []byte {
+	if n := len(line); n > 0 && line[n-1] == '\n' {
+		line = line[:n-1]
+	}
+	if n := len(line); n > 0 && line[n-1] == '\r' {
+		line = line[:n-1]
+	}
+	return line
+}
+
+// Message returns the most recently scanned message. It is only valid after
+// a call to Scan that returned true.
+func (s *MessageScanner) Message() *IRCMessage { return s.message }
+
+// Err returns the first non-EOF error encountered by Scan, if any.
+func (s *MessageScanner) Err() error { return s.err }
@@ -0,0 +1,103 @@
+package twitch
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMessageScanner(t *testing.T) {
+	input := "PING :tmi.twitch.tv\r\n" +
+		"@badge-info=;badges=broadcaster/1 :foo!foo@foo.tmi.twitch.tv PRIVMSG #foo :hello\r\n"
+
+	s := NewMessageScanner(strings.NewReader(input), ScannerOptions{})
+
+	var commands []string
+	for s.Scan() {
+		commands = append(commands, s.Message().Command)
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"PING", "PRIVMSG"}
+	if len(commands) != len(want) || commands[0] != want[0] || commands[1] != want[1] {
+		t.Fatalf("got commands %v, want %v", commands, want)
+	}
+}
+
+func TestMessageScannerMaxLineLength(t *testing.T) {
+	huge := "PRIVMSG #foo :" + strings.Repeat("a", 1024) + "\r\n"
+
+	s := NewMessageScanner(strings.NewReader(huge), ScannerOptions{MaxLineLength: 64})
+	if s.Scan() {
+		t.Fatalf("expected Scan to stop on an over-long line")
+	}
+	if s.Err() == nil {
+		t.Fatalf("expected ErrLineTooLong, got nil")
+	}
+}
+
+// infiniteReader yields an endless stream of c with no newline, simulating a
+// malicious server that never terminates a line.
+type infiniteReader struct{ c byte }
+
+func (r infiniteReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = r.c
+	}
+	return len(p), nil
+}
+
+func TestMessageScannerBoundsMemoryOnUnterminatedLine(t *testing.T) {
+	const maxLineLength = 16 * 1024
+
+	s := NewMessageScanner(infiniteReader{'a'}, ScannerOptions{MaxLineLength: maxLineLength})
+
+	// There's no newline to ever complete a line, so Scan must bail out as
+	// soon as it has read past MaxLineLength rather than buffering the
+	// unterminated line without bound; if it didn't, this would hang.
+	done := make(chan struct{})
+	go func() {
+		s.Scan()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Scan did not return promptly on an unterminated oversized line")
+	}
+
+	if s.Err() == nil {
+		t.Fatalf("expected ErrLineTooLong, got nil")
+	}
+}
+
+func TestMessageScannerSkipsLongRunsOfBlankLines(t *testing.T) {
+	// Large enough to have overflowed the stack when blank lines were
+	// skipped via unbounded recursion instead of a loop.
+	const blankLines = 3_000_000
+
+	input := strings.Repeat("\n", blankLines) + "PING :tmi.twitch.tv\r\n"
+	s := NewMessageScanner(strings.NewReader(input), ScannerOptions{})
+
+	if !s.Scan() {
+		t.Fatalf("expected a message after the blank run, got error: %v", s.Err())
+	}
+	if s.Message().Command != "PING" {
+		t.Fatalf("got command %q, want PING", s.Message().Command)
+	}
+}
+
+func TestMessageScannerTruncateLongLines(t *testing.T) {
+	huge := "PRIVMSG #foo :" + strings.Repeat("a", 1024) + "\r\n"
+
+	s := NewMessageScanner(strings.NewReader(huge), ScannerOptions{
+		MaxLineLength:     64,
+		TruncateLongLines: true,
+	})
+	if !s.Scan() {
+		t.Fatalf("expected truncated line to still parse: %v", s.Err())
+	}
+}